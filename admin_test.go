@@ -0,0 +1,110 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThatServeHTTPGetReturnsCurrentLevel(t *testing.T) {
+	l, err := NewLogger("myservice", "1.0.0", WithOutput(new(bytes.Buffer)))
+	if err != nil {
+		t.Errorf("failed to create logger instance, error: %s", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/debug/log", nil)
+	rec := httptest.NewRecorder()
+	l.ServeHTTP(rec, req)
+
+	var state adminState
+	err = json.Unmarshal(rec.Body.Bytes(), &state)
+	if err != nil {
+		t.Errorf("failed to unmarshal the admin response, error: %s", err)
+	}
+	assert.Equal(t, defaultLevel, state.Level)
+}
+
+func TestThatServeHTTPPutUpdatesLevel(t *testing.T) {
+	l, err := NewLogger("myservice", "1.0.0", WithOutput(new(bytes.Buffer)))
+	if err != nil {
+		t.Errorf("failed to create logger instance, error: %s", err)
+	}
+
+	body, _ := json.Marshal(adminState{Level: "error"})
+	req := httptest.NewRequest(http.MethodPut, "/debug/log", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+	l.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, zapLevelString(l), "error")
+}
+
+func zapLevelString(l *logger) string {
+	return l.level.Level().String()
+}
+
+func TestThatServeHTTPPutUpdatesDynamicallyAddedSinkLevel(t *testing.T) {
+	l, err := NewLogger("myservice", "1.0.0", WithOutput(io.Discard))
+	if err != nil {
+		t.Errorf("failed to create logger instance, error: %s", err)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := l.AddSink("extra", buf, "error", "json"); err != nil {
+		t.Errorf("failed to add sink, error: %s", err)
+	}
+
+	// below the sink's "error" level: must not be delivered yet
+	l.Info("should not reach extra sink")
+	assert.Empty(t, buf.String())
+
+	body, _ := json.Marshal(adminState{SinkLevels: map[string]string{"extra": "info"}})
+	req := httptest.NewRequest(http.MethodPut, "/debug/log", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+	l.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// the PUT must retune the exact sink receiving writes, not a stale copy
+	l.Info("this is a test message")
+	assert.Contains(t, buf.String(), "this is a test message")
+}
+
+func TestThatServeHTTPPutIsAllOrNothingOnInvalidSink(t *testing.T) {
+	l, err := NewLogger("myservice", "1.0.0", WithOutput(io.Discard))
+	if err != nil {
+		t.Errorf("failed to create logger instance, error: %s", err)
+	}
+
+	body, _ := json.Marshal(adminState{
+		Level: "error",
+		SinkLevels: map[string]string{
+			"does-not-exist": "debug",
+		},
+	})
+	req := httptest.NewRequest(http.MethodPut, "/debug/log", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+	l.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	// the level in the same request must not have been applied either
+	assert.Equal(t, defaultLevel, zapLevelString(l))
+}
+
+func TestThatServeHTTPPutRejectsUnknownSink(t *testing.T) {
+	l, err := NewLogger("myservice", "1.0.0", WithOutput(new(bytes.Buffer)))
+	if err != nil {
+		t.Errorf("failed to create logger instance, error: %s", err)
+	}
+
+	body, _ := json.Marshal(adminState{SinkLevels: map[string]string{"does-not-exist": "debug"}})
+	req := httptest.NewRequest(http.MethodPut, "/debug/log", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+	l.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,70 @@
+package log
+
+import (
+	"context"
+	"sync"
+)
+
+// ContextExtractor pulls structured fields out of a context.Context, e.g.
+// trace/span ids attached by OpenTelemetry instrumentation. Extractors are
+// consulted by WithContext on every call, so they should stay cheap.
+type ContextExtractor func(ctx context.Context) []interface{}
+
+// extractorsMu guards extractors: RegisterContextExtractor is typically
+// called from a middleware/plugin's init path, which can race with
+// request goroutines already calling WithContext.
+var extractorsMu sync.RWMutex
+
+// extractors are consulted, in registration order, by WithContext.
+var extractors []ContextExtractor
+
+// RegisterContextExtractor registers a field extractor that WithContext
+// consults to pull request-scoped fields (trace_id, span_id, user_id,
+// tenant_id, etc.) out of a context.Context, so callers don't have to
+// rewrite every log line to carry them explicitly.
+func RegisterContextExtractor(fn ContextExtractor) {
+	extractorsMu.Lock()
+	defer extractorsMu.Unlock()
+	extractors = append(extractors, fn)
+}
+
+// loggerKey is the context key under which NewContext stores a Logger.
+type loggerKey struct{}
+
+// NewContext returns a copy of ctx carrying l, retrievable later via
+// FromContext.
+func NewContext(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// FromContext returns the logger stored in ctx by NewContext with any
+// registered ContextExtractor fields applied. If ctx carries no logger, l
+// is used as the base instead.
+func (l *logger) FromContext(ctx context.Context) Logger {
+	var base Logger = l
+	if stored, ok := ctx.Value(loggerKey{}).(Logger); ok {
+		base = stored
+	}
+	return base.WithContext(ctx)
+}
+
+// WithContext returns a logger with the fields pulled from ctx by every
+// registered ContextExtractor added to its context. It does not store l
+// back into ctx; use NewContext for that.
+func (l *logger) WithContext(ctx context.Context) Logger {
+	extractorsMu.RLock()
+	current := extractors
+	extractorsMu.RUnlock()
+
+	if len(current) == 0 {
+		return l
+	}
+	var keyvals []interface{}
+	for _, extract := range current {
+		keyvals = append(keyvals, extract(ctx)...)
+	}
+	if len(keyvals) == 0 {
+		return l
+	}
+	return l.With(keyvals...)
+}
@@ -0,0 +1,73 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type traceIDKey struct{}
+
+func TestThatWithContextAddsExtractedFields(t *testing.T) {
+	RegisterContextExtractor(func(ctx context.Context) []interface{} {
+		traceID, _ := ctx.Value(traceIDKey{}).(string)
+		return []interface{}{"trace_id", traceID}
+	})
+
+	buf := new(bytes.Buffer)
+	l, err := NewLogger("myservice", "1.0.0", WithOutput(buf))
+	if err != nil {
+		t.Errorf("failed to create logger instance, error: %s", err)
+	}
+
+	ctx := context.WithValue(context.Background(), traceIDKey{}, "abc-123")
+	l.WithContext(ctx).Info("this is a test message")
+
+	var tmpLog map[string]interface{}
+	err = json.Unmarshal(buf.Bytes(), &tmpLog)
+	if err != nil {
+		t.Errorf("failed to unmarshal the generated log, error: %s", err)
+	}
+	assert.Equal(t, "abc-123", tmpLog["trace_id"])
+}
+
+func TestThatRegisterContextExtractorIsSafeForConcurrentUse(t *testing.T) {
+	l, err := NewLogger("myservice", "1.0.0", WithOutput(new(bytes.Buffer)))
+	if err != nil {
+		t.Errorf("failed to create logger instance, error: %s", err)
+	}
+
+	ctx := context.WithValue(context.Background(), traceIDKey{}, "concurrent")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		RegisterContextExtractor(func(ctx context.Context) []interface{} {
+			return []interface{}{"concurrent_field", "value"}
+		})
+	}()
+	l.WithContext(ctx).Info("this is a test message")
+	<-done
+}
+
+func TestThatFromContextReturnsStoredLogger(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l, err := NewLogger("myservice", "1.0.0", WithOutput(buf))
+	if err != nil {
+		t.Errorf("failed to create logger instance, error: %s", err)
+	}
+
+	ctx := NewContext(context.Background(), l)
+	assert.NotNil(t, l.FromContext(ctx))
+
+	// a different receiver must still hand back the logger stored in ctx
+	other, err := NewLogger("myservice", "1.0.0", WithOutput(io.Discard))
+	if err != nil {
+		t.Errorf("failed to create logger instance, error: %s", err)
+	}
+	other.FromContext(ctx).Info("this is a test message")
+	assert.Contains(t, buf.String(), "this is a test message")
+}
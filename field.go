@@ -0,0 +1,50 @@
+package log
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Field is a strongly-typed log field, a re-export of zapcore.Field. Build
+// one with the constructors below and pass it to DebugF/InfoF/WarnF/ErrorF
+// to avoid the reflection and allocation cost of the keyvals API.
+type Field = zapcore.Field
+
+// String constructs a Field carrying a string value.
+func String(key, val string) Field {
+	return zap.String(key, val)
+}
+
+// Int constructs a Field carrying an int value.
+func Int(key string, val int) Field {
+	return zap.Int(key, val)
+}
+
+// Int64 constructs a Field carrying an int64 value.
+func Int64(key string, val int64) Field {
+	return zap.Int64(key, val)
+}
+
+// Bool constructs a Field carrying a bool value.
+func Bool(key string, val bool) Field {
+	return zap.Bool(key, val)
+}
+
+// Duration constructs a Field carrying a time.Duration value.
+func Duration(key string, val time.Duration) Field {
+	return zap.Duration(key, val)
+}
+
+// Error constructs a Field carrying err under the conventional "error" key.
+func Error(err error) Field {
+	return zap.Error(err)
+}
+
+// Any constructs a Field by reflecting on val's type, for the rare case
+// where a dedicated constructor doesn't fit. Prefer a typed constructor
+// when one is available.
+func Any(key string, val interface{}) Field {
+	return zap.Any(key, val)
+}
@@ -0,0 +1,30 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThatInfoFWritesTypedFields(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l, err := NewLogger("myservice", "1.0.0", WithOutput(buf))
+	if err != nil {
+		t.Errorf("failed to create logger instance, error: %s", err)
+	}
+
+	l.InfoF("this is a test message", String("user_id", "u-1"), Int("attempt", 3), Error(errors.New("boom")))
+
+	var tmpLog map[string]interface{}
+	err = json.Unmarshal(buf.Bytes(), &tmpLog)
+	if err != nil {
+		t.Errorf("failed to unmarshal the generated log, error: %s", err)
+	}
+	assert.Equal(t, "this is a test message", tmpLog["msg"])
+	assert.Equal(t, "u-1", tmpLog["user_id"])
+	assert.Equal(t, float64(3), tmpLog["attempt"])
+	assert.Equal(t, "boom", tmpLog["error"])
+}
@@ -1,12 +1,13 @@
-// go:build !windows
 package log
 
 import (
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/natefinch/lumberjack"
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -43,6 +44,18 @@ type config struct {
 	out io.Writer
 	// instance ID of the service
 	instanceID string
+	// sinks added via SinkOption, each with its own encoding and level
+	sinks []sinkSpec
+
+	// sampling options set via WithSampling
+	samplingEnabled    bool
+	samplingInitial    int
+	samplingThereafter int
+	samplingTick       time.Duration
+
+	// rate limit options set via WithRateLimit
+	rateLimitEnabled bool
+	rateLimitPerKey  int
 }
 
 // An Option configures a logger.
@@ -105,6 +118,30 @@ func WithInstanceID(instanceID string) Option {
 	}
 }
 
+// WithSampling caps logging, per level and message, to the first initial
+// entries seen within each tick window, then only every thereafter-th entry
+// after that, to keep hot
+// loops from letting uncapped logging dominate CPU and disk I/O. It can be
+// retuned or disabled at runtime via SetSampling.
+func WithSampling(initial, thereafter int, tick time.Duration) Option {
+	return func(log *logger) {
+		log.config.samplingEnabled = true
+		log.config.samplingInitial = initial
+		log.config.samplingThereafter = thereafter
+		log.config.samplingTick = tick
+	}
+}
+
+// WithRateLimit de-duplicates repeated messages by (level, msg) key,
+// logging at most perKeyPerSecond occurrences of any one key per second. It
+// can be retuned or disabled at runtime via SetRateLimit.
+func WithRateLimit(perKeyPerSecond int) Option {
+	return func(log *logger) {
+		log.config.rateLimitEnabled = true
+		log.config.rateLimitPerKey = perKeyPerSecond
+	}
+}
+
 // logger used for loggin purpose through out the application
 type logger struct {
 	// Zap Sugared logger
@@ -118,6 +155,20 @@ type logger struct {
 
 	// logging configuration
 	config *config
+
+	// registry holds the sinks this logger currently writes to, and allows
+	// them to be added or removed at runtime
+	registry *sinkRegistry
+
+	// samplingCfg and rateLimitCfg back SetSampling/SetRateLimit; they are
+	// shared with every logger derived via With so the toggles apply
+	// consistently across them
+	samplingCfg  *samplingConfig
+	rateLimitCfg *rateLimitConfig
+
+	// zl is the non-sugared *zap.Logger backing the *F methods, avoiding the
+	// SugaredLogger's reflection-based keyval handling on hot paths
+	zl *zap.Logger
 }
 
 // NewLogger is a wrapper create around Zap logger
@@ -146,15 +197,22 @@ func NewLogger(app, version string, opts ...Option) (*logger, error) {
 	cfg := zap.NewProductionConfig()
 	cfg.DisableCaller = true
 
-	cfg.EncoderConfig.LevelKey = levelKey
-	cfg.EncoderConfig.MessageKey = messageKey
-	cfg.EncoderConfig.TimeKey = timeKey
-	cfg.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
-	cfg.EncoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+	enc, err := newEncoder(l.config.encoding, false)
+	if err != nil {
+		return nil, err
+	}
 
-	cfg.Encoding = l.config.encoding
+	// registry holds the sinks this logger writes to; sinks can be added or
+	// removed at runtime via AddSink/RemoveSink without rebuilding the logger.
+	// service/version/instance_id are baked into its child cores directly
+	// (see sinkRegistry.fields) rather than applied via zap.Fields, so
+	// cfg.Build never forks a second registry out from under l.registry.
+	registry := newSinkRegistry(
+		zap.String("service", app),
+		zap.String("version", version),
+		zap.String("instance_id", l.config.instanceID),
+	)
 
-	targets := []zapcore.WriteSyncer{}
 	if l.config.syslogConn != "" {
 		// Initialize a syslog writer
 		Syslog, err := GetSyslog(l.config.syslogProtocol, l.config.syslogConn, fmt.Sprintf("%s-%s", app, version))
@@ -162,31 +220,50 @@ func NewLogger(app, version string, opts ...Option) (*logger, error) {
 			return nil, err
 		}
 		if Syslog != nil {
-			targets = append(targets, zapcore.AddSync(Syslog))
+			syslogCloser, _ := Syslog.(io.Closer)
+			registry.addCore("syslog", zapcore.NewCore(enc, zapcore.AddSync(Syslog), level), level, syslogCloser)
 		}
 	}
 
 	if l.config.out != nil {
-		targets = append(targets, zapcore.AddSync(l.config.out))
+		outCloser, _ := l.config.out.(io.Closer)
+		registry.addCore("output", zapcore.NewCore(enc, zapcore.AddSync(l.config.out), level), level, outCloser)
 	}
 
 	// create log rotator
 	var lr *lumberjack.Logger
 	if l.config.encoding == defaultEncoding {
 		lr = getLogRotator(l.config)
-		targets = append(targets, zapcore.AddSync(lr))
+		registry.addCore("file", zapcore.NewCore(enc, zapcore.AddSync(lr), level), level, lr)
 	}
 
-	// create multi write syncer for configured targets
-	syncer := zapcore.NewMultiWriteSyncer(targets...)
+	// sinks added via WithConsoleSink/WithFileSink/WithSyslogSink each carry
+	// their own encoding and level, on top of the shared targets above
+	for _, spec := range l.config.sinks {
+		sinkCore, sinkLevel, sinkCloser, err := spec.build(app, version)
+		if err != nil {
+			return nil, err
+		}
+		registry.addCore(spec.name, sinkCore, sinkLevel, sinkCloser)
+	}
+
+	rateLimitCfg := &rateLimitConfig{}
+	if l.config.rateLimitEnabled {
+		rateLimitCfg.set(l.config.rateLimitPerKey)
+	}
+	sampCfg := &samplingConfig{}
+	if l.config.samplingEnabled {
+		sampCfg.set(l.config.samplingInitial, l.config.samplingThereafter, l.config.samplingTick)
+	}
+
+	var top zapcore.Core = registry
+	top = &rateLimitCore{inner: top, cfg: rateLimitCfg}
+	top = &samplingCore{inner: top, cfg: sampCfg}
 
 	sl, err := cfg.Build(
-		withWrapCore(syncer, cfg, level),
-		zap.Fields(
-			zap.String("service", app),
-			zap.String("version", version),
-			zap.String("instance_id", l.config.instanceID),
-		),
+		zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return top
+		}),
 	)
 	if err != nil {
 		return nil, err
@@ -194,27 +271,39 @@ func NewLogger(app, version string, opts ...Option) (*logger, error) {
 	defer sl.Sync()
 	l.SugaredLogger = sl.Sugar()
 	l.level = level
+	l.registry = registry
+	l.samplingCfg = sampCfg
+	l.rateLimitCfg = rateLimitCfg
+	l.zl = sl
 	if lr != nil {
 		l.rotator = lr
 	}
 	return l, nil
 }
 
-// withWrapCore replaces existing Core with new, that writes to passed WriteSyncer.
-func withWrapCore(ws zapcore.WriteSyncer, conf zap.Config, level zap.AtomicLevel) zap.Option {
-	var enc zapcore.Encoder
-	switch conf.Encoding {
+// newEncoder builds the zapcore.Encoder for the given encoding ("json" or
+// "console"). When colorized is true, console output encodes levels with
+// their ANSI colors.
+func newEncoder(encoding string, colorized bool) (zapcore.Encoder, error) {
+	encCfg := zap.NewProductionEncoderConfig()
+	encCfg.LevelKey = levelKey
+	encCfg.MessageKey = messageKey
+	encCfg.TimeKey = timeKey
+	encCfg.EncodeTime = zapcore.ISO8601TimeEncoder
+	if colorized {
+		encCfg.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	} else {
+		encCfg.EncodeLevel = zapcore.CapitalLevelEncoder
+	}
+
+	switch encoding {
 	case "json":
-		enc = zapcore.NewJSONEncoder(conf.EncoderConfig)
+		return zapcore.NewJSONEncoder(encCfg), nil
 	case "console":
-		enc = zapcore.NewConsoleEncoder(conf.EncoderConfig)
+		return zapcore.NewConsoleEncoder(encCfg), nil
 	default:
-		panic("unknown encoding")
+		return nil, fmt.Errorf("unknown encoding %q", encoding)
 	}
-
-	return zap.WrapCore(func(core zapcore.Core) zapcore.Core {
-		return zapcore.NewCore(enc, ws, level)
-	})
 }
 
 // returns a lumberjack logger instance
@@ -229,24 +318,33 @@ func getLogRotator(conf *config) *lumberjack.Logger {
 }
 
 func getZapLogLevel(level string) zapcore.Level {
-	var logLevel zapcore.Level
+	logLevel, ok := parseZapLogLevel(level)
+	if !ok {
+		panic(fmt.Sprintf("unsupported log level %s. debug, info, warn, error, panic and fatal are the only supported loglevels.", level))
+	}
+	return logLevel
+}
+
+// parseZapLogLevel is the non-panicking counterpart of getZapLogLevel, used
+// where an unrecognized level should be reported as an error instead of
+// crashing the process, e.g. the admin HTTP endpoint.
+func parseZapLogLevel(level string) (zapcore.Level, bool) {
 	switch level {
 	case "debug":
-		logLevel = zap.DebugLevel
+		return zap.DebugLevel, true
 	case "info":
-		logLevel = zap.InfoLevel
+		return zap.InfoLevel, true
 	case "warn":
-		logLevel = zap.WarnLevel
+		return zap.WarnLevel, true
 	case "error":
-		logLevel = zap.ErrorLevel
+		return zap.ErrorLevel, true
 	case "panic":
-		logLevel = zap.PanicLevel
+		return zap.PanicLevel, true
 	case "fatal":
-		logLevel = zap.FatalLevel
+		return zap.FatalLevel, true
 	default:
-		panic(fmt.Sprintf("unsupported log level %s. debug, info, warn, error, panic and fatal are the only supported loglevels.", level))
+		return 0, false
 	}
-	return logLevel
 }
 
 // Debug logs a debug message to the zap logger
@@ -279,6 +377,38 @@ func (l *logger) Fatal(msg string, keyvals ...interface{}) {
 	l.Fatalw(msg, keyvals...)
 }
 
+// DebugF logs a debug message using strongly-typed fields. Unlike Debug, it
+// calls the underlying *zap.Logger directly, skipping the SugaredLogger's
+// reflection-based keyval handling, for callers on hot paths.
+func (l *logger) DebugF(msg string, fields ...Field) {
+	l.zl.Debug(msg, fields...)
+}
+
+// InfoF logs an info message using strongly-typed fields. See DebugF.
+func (l *logger) InfoF(msg string, fields ...Field) {
+	l.zl.Info(msg, fields...)
+}
+
+// WarnF logs a warning message using strongly-typed fields. See DebugF.
+func (l *logger) WarnF(msg string, fields ...Field) {
+	l.zl.Warn(msg, fields...)
+}
+
+// ErrorF logs an error message using strongly-typed fields. See DebugF.
+func (l *logger) ErrorF(msg string, fields ...Field) {
+	l.zl.Error(msg, fields...)
+}
+
+// PanicF logs a panic message using strongly-typed fields. See DebugF.
+func (l *logger) PanicF(msg string, fields ...Field) {
+	l.zl.Panic(msg, fields...)
+}
+
+// FatalF logs a fatal message using strongly-typed fields. See DebugF.
+func (l *logger) FatalF(msg string, fields ...Field) {
+	l.zl.Fatal(msg, fields...)
+}
+
 // Debugf logs a formatted debug message
 func (l *logger) Debugf(format string, args ...interface{}) {
 	l.SugaredLogger.Debugf(format, args...)
@@ -291,7 +421,8 @@ func (l *logger) Printf(format string, args ...interface{}) {
 
 // With returns a new logger with the provided keyvals added to its context
 func (l *logger) With(keyvals ...interface{}) Logger {
-	return &logger{l.SugaredLogger.With(keyvals...), nil, l.level, l.config}
+	sugared := l.SugaredLogger.With(keyvals...)
+	return &logger{sugared, nil, l.level, l.config, l.registry, l.samplingCfg, l.rateLimitCfg, sugared.Desugar()}
 }
 
 // SetLogLevel update the current logging level to the supplied one
@@ -323,3 +454,38 @@ func (l *logger) SetLogRotationSize(size int) {
 func (l *logger) SetLogRotationCount(count int) {
 	l.rotator.MaxBackups = count
 }
+
+// SetSampling retunes sampling to log, per level and message, the first
+// initial entries seen within each tick window, then only every
+// thereafter-th entry after that. Passing
+// initial<=0 disables sampling again.
+func (l *logger) SetSampling(initial, thereafter int, tick time.Duration) {
+	if initial <= 0 {
+		l.samplingCfg.disable()
+		return
+	}
+	l.samplingCfg.set(initial, thereafter, tick)
+}
+
+// SetRateLimit retunes rate limiting to log at most perKeyPerSecond
+// occurrences of any one (level, msg) key per second. Passing
+// perKeyPerSecond<=0 disables rate limiting again.
+func (l *logger) SetRateLimit(perKeyPerSecond int) {
+	if perKeyPerSecond <= 0 {
+		l.rateLimitCfg.disable()
+		return
+	}
+	l.rateLimitCfg.set(perKeyPerSecond)
+}
+
+// Close flushes and closes every sink registered on the logger, including
+// ones configured via WithConsoleSink/WithFileSink/WithSyslogSink/
+// WithRemoteSink and ones attached after construction via AddSink,
+// releasing any file handle, socket or
+// background goroutine they hold. It should be called once, during process
+// shutdown.
+func (l *logger) Close() error {
+	syncErr := l.registry.Sync()
+	closeErr := l.registry.closeAll()
+	return multierr.Append(syncErr, closeErr)
+}
@@ -1,5 +1,12 @@
 package log
 
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
 // A common logger interface to be used throughout  platfrom components
 type Logger interface {
 	Debug(msg string, keyvals ...interface{})
@@ -14,16 +21,60 @@ type Logger interface {
 
 	Fatal(msg string, keyvals ...interface{})
 
+	// DebugF, InfoF, WarnF, ErrorF, PanicF and FatalF are strongly-typed
+	// equivalents of Debug, Info, Warn, Error, Panic and Fatal. They call
+	// the underlying *zap.Logger directly instead of the SugaredLogger,
+	// avoiding its reflection-based keyval handling for hot paths.
+	DebugF(msg string, fields ...Field)
+	InfoF(msg string, fields ...Field)
+	WarnF(msg string, fields ...Field)
+	ErrorF(msg string, fields ...Field)
+	PanicF(msg string, fields ...Field)
+	FatalF(msg string, fields ...Field)
+
 	Printf(format string, args ...interface{})
 
 	Debugf(format string, args ...interface{})
 
 	With(keyvals ...interface{}) Logger
 
+	// FromContext returns the logger stored in ctx via NewContext, falling
+	// back to the receiver, with registered ContextExtractor fields applied
+	FromContext(ctx context.Context) Logger
+
+	// WithContext returns a logger carrying the fields that registered
+	// ContextExtractors pull out of ctx (trace_id, span_id, user_id, etc.)
+	WithContext(ctx context.Context) Logger
+
 	// Set log level
 	SetLogLevel(level string)
 	// Set log rotation size
 	SetLogRotationSize(size int)
 	// Set log rotation count
 	SetLogRotationCount(count int)
+
+	// AddSink attaches a named sink writing to w at level and encoding,
+	// replacing any existing sink registered under the same name
+	AddSink(name string, w io.Writer, level string, encoding string) error
+	// RemoveSink detaches the named sink
+	RemoveSink(name string) error
+	// ListSinks returns the names of all currently registered sinks
+	ListSinks() []string
+	// SetSinkLevel atomically updates the minimum level for the named sink
+	SetSinkLevel(name, level string) error
+
+	// SetSampling retunes sampling at runtime; initial<=0 disables it
+	SetSampling(initial, thereafter int, tick time.Duration)
+	// SetRateLimit retunes per-key rate limiting at runtime; perKeyPerSecond<=0 disables it
+	SetRateLimit(perKeyPerSecond int)
+
+	// ServeHTTP implements zap's AtomicLevel HTTP handler contract,
+	// extended with rotation and per-sink level controls. See RegisterAdmin.
+	http.Handler
+	// RegisterAdmin registers the logger's admin endpoint at /debug/log on mux
+	RegisterAdmin(mux *http.ServeMux)
+
+	// Close flushes and closes every sink, releasing any file handle,
+	// socket or background goroutine they hold. Call once during shutdown.
+	Close() error
 }
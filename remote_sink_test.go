@@ -0,0 +1,69 @@
+package log
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingSink is a RemoteSink test double that records every delivered
+// write.
+type recordingSink struct {
+	mu     sync.Mutex
+	writes []string
+}
+
+func (s *recordingSink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	s.writes = append(s.writes, string(p))
+	s.mu.Unlock()
+	return len(p), nil
+}
+
+func (s *recordingSink) Close() error { return nil }
+
+func (s *recordingSink) delivered() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.writes...)
+}
+
+func TestThatBufferedRemoteSinkDeliversWrites(t *testing.T) {
+	rec := &recordingSink{}
+	buffered := newBufferedRemoteSink(rec, 10)
+	defer buffered.Close()
+
+	_, err := buffered.Write([]byte("hello"))
+	if err != nil {
+		t.Errorf("failed to write, error: %s", err)
+	}
+
+	assert.Eventually(t, func() bool {
+		return len(rec.delivered()) == 1
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestThatBufferedRemoteSinkDropsOldestWhenFull(t *testing.T) {
+	rec := &recordingSink{}
+	buffered := newBufferedRemoteSink(rec, 1)
+	defer buffered.Close()
+
+	// fill the buffer beyond capacity before the drain goroutine can empty
+	// it, forcing the drop-oldest path
+	for i := 0; i < 5; i++ {
+		_, err := buffered.Write([]byte(fmt.Sprintf("entry-%d", i)))
+		if err != nil {
+			t.Errorf("failed to write, error: %s", err)
+		}
+	}
+
+	// dropping the oldest entries never discards the newest one, so it is
+	// always the last entry delivered
+	assert.Eventually(t, func() bool {
+		delivered := rec.delivered()
+		return len(delivered) > 0 && delivered[len(delivered)-1] == "entry-4"
+	}, time.Second, 10*time.Millisecond)
+}
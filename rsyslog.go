@@ -1,22 +1,120 @@
-//go:build !windows
-// +build !windows
-
 package log
 
 import (
+	"crypto/tls"
+	"fmt"
 	"io"
-	"log/syslog"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// syslogFacilityDaemon and syslogSeverityDebug match the
+// LOG_DAEMON|LOG_DEBUG priority this package has always logged at.
+const (
+	syslogFacilityDaemon = 3
+	syslogSeverityDebug  = 7
+	syslogPriority       = syslogFacilityDaemon*8 + syslogSeverityDebug
 )
 
-func GetSyslog(protocol, conn, tag string) (io.Writer, error) {
-	sysLog, err := syslog.Dial(
-		protocol,
-		conn,
-		syslog.LOG_DEBUG|syslog.LOG_DAEMON,
-		tag,
-	)
+// syslogWriter is a pure-Go RFC5424 syslog client. Unlike the stdlib
+// log/syslog package, it dials plain TCP/UDP/TLS itself instead of relying
+// on a platform syslog daemon socket, so it works on Windows as well as
+// Unix.
+type syslogWriter struct {
+	proto    string
+	addr     string
+	tag      string
+	hostname string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// GetSyslog dials a syslog server at addr over protocol ("tcp", "udp" or
+// "tls") and returns a writer that frames every Write as one RFC5424
+// message tagged with tag, reconnecting automatically if the connection is
+// lost.
+func GetSyslog(protocol, addr, tag string) (io.Writer, error) {
+	hostname, err := os.Hostname()
 	if err != nil {
+		hostname = "-"
+	}
+	w := &syslogWriter{proto: protocol, addr: addr, tag: tag, hostname: hostname}
+	if err := w.connect(); err != nil {
 		return nil, err
 	}
-	return sysLog, nil
+	return w, nil
+}
+
+func (w *syslogWriter) connect() error {
+	var conn net.Conn
+	var err error
+	if w.proto == "tls" {
+		conn, err = tls.Dial("tcp", w.addr, nil)
+	} else {
+		conn, err = net.Dial(w.proto, w.addr)
+	}
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	w.conn = conn
+	w.mu.Unlock()
+	return nil
+}
+
+func (w *syslogWriter) frame(p []byte) string {
+	return fmt.Sprintf("<%d>1 %s %s %s - - - %s\n",
+		syslogPriority, time.Now().UTC().Format(time.RFC3339), w.hostname, w.tag, strings.TrimRight(string(p), "\n"))
+}
+
+// Write sends p as a single RFC5424 message, reconnecting once and retrying
+// if the current connection has gone bad.
+func (w *syslogWriter) Write(p []byte) (int, error) {
+	msg := []byte(w.frame(p))
+
+	w.mu.Lock()
+	conn := w.conn
+	w.mu.Unlock()
+
+	if conn == nil {
+		if err := w.connect(); err != nil {
+			return 0, err
+		}
+		w.mu.Lock()
+		conn = w.conn
+		w.mu.Unlock()
+	}
+
+	if _, err := conn.Write(msg); err != nil {
+		w.mu.Lock()
+		w.conn = nil
+		w.mu.Unlock()
+		if reErr := w.connect(); reErr != nil {
+			return 0, err
+		}
+		w.mu.Lock()
+		conn = w.conn
+		w.mu.Unlock()
+		if _, err := conn.Write(msg); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Close closes the underlying connection.
+func (w *syslogWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
 }
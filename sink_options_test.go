@@ -0,0 +1,38 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThatWithConsoleSinkRegistersConsoleSink(t *testing.T) {
+	l, err := NewLogger("myservice", "1.0.0", WithConsoleSink("info", false))
+	if err != nil {
+		t.Errorf("failed to create logger instance, error: %s", err)
+	}
+	assert.Contains(t, l.ListSinks(), "console")
+}
+
+func TestThatWithConsoleSinkErrorsForUnknownLevel(t *testing.T) {
+	_, err := NewLogger("myservice", "1.0.0", WithConsoleSink("typo", false))
+	assert.Error(t, err)
+}
+
+func TestThatConsoleSinkDoesNotAffectJSONOutputSink(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l, err := NewLogger("myservice", "1.0.0", WithOutput(buf), WithConsoleSink("info", false))
+	if err != nil {
+		t.Errorf("failed to create logger instance, error: %s", err)
+	}
+
+	l.Info("this is a test message")
+	var tmpLog map[string]interface{}
+	err = json.Unmarshal(buf.Bytes(), &tmpLog)
+	if err != nil {
+		t.Errorf("failed to unmarshal the generated log, error: %s", err)
+	}
+	assert.Equal(t, "this is a test message", tmpLog["msg"])
+}
@@ -0,0 +1,272 @@
+package log
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// sink is a single named entry in a sinkRegistry. level is the same
+// zap.AtomicLevel the sink's core was built with, kept around so
+// SetSinkLevel can retune it live without rebuilding the core. closer is
+// optional: it is the sink's destination itself whenever that destination
+// implements io.Closer (a *lumberjack.Logger, a syslogWriter, a
+// bufferedRemoteSink, or a caller-supplied writer such as WithOutput's or
+// AddSink's), and is closed on RemoveSink/Close; it is nil when the
+// destination does not implement io.Closer.
+type sink struct {
+	core   zapcore.Core
+	level  zap.AtomicLevel
+	closer io.Closer
+}
+
+// sinkRegistry is a zapcore.Core that fans log entries out to a dynamic set
+// of named child cores, each with its own encoder and level. Sinks can be
+// attached and detached at runtime via AddSink/RemoveSink without rebuilding
+// the logger. mu and sinks are shared by every registry forked off the root
+// via With, so AddSink/RemoveSink/SetSinkLevel called on the root (or any
+// derived Logger) are observed by every Logger derived from it, including
+// ones obtained via With/WithContext before or after the call.
+//
+// NewLogger must keep exactly one set of sinks alive for the lifetime of the
+// logger: the zapcore.Core contract requires With to return a new,
+// independent core rather than mutate the receiver, so a naive With that
+// snapshots the sinks map would freeze the sink topology at fork time for
+// every derived Logger (see withFields below for how per-fork fields are
+// layered on without doing that). Likewise, baking service/version/
+// instance_id into each core at addCore time (see fields below), rather than
+// via zap.Fields on the whole registry, means cfg.Build never needs to fork
+// the root registry either.
+type sinkRegistry struct {
+	mu    *sync.RWMutex
+	sinks map[string]*sink
+
+	// fields are applied to every core registered via addCore: the
+	// service/version/instance_id baked in at construction time.
+	fields []zapcore.Field
+
+	// withFields are additional fields layered on by a With call, applied to
+	// each sink's core on demand in cores() rather than baked into the
+	// shared sinks map, so the sinks themselves stay shared across forks.
+	withFields []zapcore.Field
+}
+
+// newSinkRegistry returns an empty sinkRegistry whose child cores will all
+// carry fields, applied by addCore.
+func newSinkRegistry(fields ...zapcore.Field) *sinkRegistry {
+	return &sinkRegistry{mu: &sync.RWMutex{}, sinks: make(map[string]*sink), fields: fields}
+}
+
+// cores returns a snapshot of the currently registered child cores, each
+// carrying this registry's withFields (if any).
+func (r *sinkRegistry) cores() []zapcore.Core {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	cores := make([]zapcore.Core, 0, len(r.sinks))
+	for _, s := range r.sinks {
+		c := s.core
+		if len(r.withFields) > 0 {
+			c = c.With(r.withFields)
+		}
+		cores = append(cores, c)
+	}
+	return cores
+}
+
+// Enabled implements zapcore.Core.
+func (r *sinkRegistry) Enabled(lvl zapcore.Level) bool {
+	for _, c := range r.cores() {
+		if c.Enabled(lvl) {
+			return true
+		}
+	}
+	return false
+}
+
+// With implements zapcore.Core. The returned registry shares this
+// registry's sinks map and mutex, so AddSink/RemoveSink/SetSinkLevel called
+// on either one is observed by both; only the extra fields are layered on
+// per fork, applied to each sink's core on demand by cores().
+func (r *sinkRegistry) With(fields []zapcore.Field) zapcore.Core {
+	return &sinkRegistry{
+		mu:         r.mu,
+		sinks:      r.sinks,
+		fields:     r.fields,
+		withFields: append(append([]zapcore.Field(nil), r.withFields...), fields...),
+	}
+}
+
+// Check implements zapcore.Core, delegating to every registered sink so
+// each one gets a chance to add itself to the CheckedEntry.
+func (r *sinkRegistry) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	for _, c := range r.cores() {
+		ce = c.Check(ent, ce)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core, writing to every registered sink and
+// aggregating errors so a slow or failing sink doesn't drop the others. In
+// practice zap never calls this directly: Check appends each child core to
+// the CheckedEntry individually (see Check above), and CheckedEntry.Write
+// writes to each of them itself. This Write exists so sinkRegistry is a
+// complete, independently usable zapcore.Core, but the multierr aggregation
+// below is dead code on the normal logging path.
+func (r *sinkRegistry) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	var err error
+	for _, c := range r.cores() {
+		err = multierr.Append(err, c.Write(ent, fields))
+	}
+	return err
+}
+
+// Sync implements zapcore.Core, flushing every registered sink.
+func (r *sinkRegistry) Sync() error {
+	var err error
+	for _, c := range r.cores() {
+		err = multierr.Append(err, c.Sync())
+	}
+	return err
+}
+
+// closeAll closes every registered sink's closer, aggregating errors via
+// multierr so one failing sink doesn't stop the others from closing.
+func (r *sinkRegistry) closeAll() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var err error
+	for _, s := range r.sinks {
+		if s.closer != nil {
+			err = multierr.Append(err, s.closer.Close())
+		}
+	}
+	return err
+}
+
+// addCore registers a pre-built core under name, replacing any sink already
+// registered under that name. level is the zap.AtomicLevel the core was
+// built with, so SetSinkLevel can retune it later. closer, if non-nil, is
+// closed when the sink is later removed via RemoveSink or the logger is
+// closed via Close. The registry's fields (service/version/instance_id) are
+// applied here rather than via the registry's own With, so the registry
+// never needs to be forked to pick them up.
+func (r *sinkRegistry) addCore(name string, core zapcore.Core, level zap.AtomicLevel, closer io.Closer) {
+	if len(r.fields) > 0 {
+		core = core.With(r.fields)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sinks[name] = &sink{core: core, level: level, closer: closer}
+}
+
+// checkSinkLevel validates that name is a registered sink and level is
+// recognized, without mutating anything. Callers that need to apply several
+// sink level changes as one all-or-nothing request (e.g. the admin HTTP
+// endpoint) should call this for every change up front, before calling
+// setSinkLevel for any of them.
+func (r *sinkRegistry) checkSinkLevel(name, level string) error {
+	if _, ok := parseZapLogLevel(level); !ok {
+		return fmt.Errorf("unrecognized level %q", level)
+	}
+	r.mu.RLock()
+	_, ok := r.sinks[name]
+	r.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("sink %q is not registered", name)
+	}
+	return nil
+}
+
+// setSinkLevel atomically updates the minimum level for the named sink,
+// without rebuilding the logger. It returns an error if no sink is
+// registered under that name, or level is unrecognized. The "output",
+// "file" and "syslog" sinks built by NewLogger share the logger's own
+// level, so setting any one of them also changes the other two and the
+// level reported and set by SetLogLevel.
+func (r *sinkRegistry) setSinkLevel(name, level string) error {
+	zapLevel, ok := parseZapLogLevel(level)
+	if !ok {
+		return fmt.Errorf("unrecognized level %q", level)
+	}
+
+	r.mu.RLock()
+	s, ok := r.sinks[name]
+	r.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("sink %q is not registered", name)
+	}
+	s.level.SetLevel(zapLevel)
+	return nil
+}
+
+// AddSink attaches a named sink writing w-formatted log entries at or above
+// level to the logger. It replaces any existing sink registered under the
+// same name, so operators can swap a sink's destination, level or encoding
+// without rebuilding the logger. If w implements io.Closer, it is closed
+// when the sink is later removed via RemoveSink or the logger is closed via
+// Close.
+func (l *logger) AddSink(name string, w io.Writer, level string, encoding string) error {
+	if name == "" {
+		return fmt.Errorf("sink name must not be empty")
+	}
+	enc, err := newEncoder(encoding, false)
+	if err != nil {
+		return err
+	}
+	zapLevel, ok := parseZapLogLevel(level)
+	if !ok {
+		return fmt.Errorf("unrecognized level %q", level)
+	}
+	al := zap.NewAtomicLevelAt(zapLevel)
+	core := zapcore.NewCore(enc, zapcore.AddSync(w), al)
+	closer, _ := w.(io.Closer)
+	l.registry.addCore(name, core, al, closer)
+	return nil
+}
+
+// SetSinkLevel atomically updates the minimum level for the named sink,
+// without rebuilding the logger. Sinks added via AddSink or the
+// With*Sink/WithRemoteSink options each have their own level; the "output",
+// "file" and "syslog" sinks share the logger's level, so setting one of
+// them changes all three and the logger-wide level. See setSinkLevel.
+func (l *logger) SetSinkLevel(name, level string) error {
+	return l.registry.setSinkLevel(name, level)
+}
+
+// RemoveSink detaches the named sink, closing its destination if it holds a
+// file handle, socket or background goroutine that needs releasing (see
+// sink.closer). It returns an error if no sink is registered under that
+// name, or if closing its destination fails; the sink is detached either
+// way.
+func (l *logger) RemoveSink(name string) error {
+	l.registry.mu.Lock()
+	s, ok := l.registry.sinks[name]
+	if !ok {
+		l.registry.mu.Unlock()
+		return fmt.Errorf("sink %q is not registered", name)
+	}
+	delete(l.registry.sinks, name)
+	l.registry.mu.Unlock()
+
+	if s.closer != nil {
+		if err := s.closer.Close(); err != nil {
+			return fmt.Errorf("sink %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// ListSinks returns the names of all currently registered sinks.
+func (l *logger) ListSinks() []string {
+	l.registry.mu.RLock()
+	defer l.registry.mu.RUnlock()
+	names := make([]string, 0, len(l.registry.sinks))
+	for name := range l.registry.sinks {
+		names = append(names, name)
+	}
+	return names
+}
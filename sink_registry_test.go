@@ -0,0 +1,159 @@
+package log
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// closingWriter is an io.Writer + io.Closer test double that records
+// whether Close was called.
+type closingWriter struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (w *closingWriter) Close() error {
+	w.closed = true
+	return nil
+}
+
+func TestThatAddSinkWritesToNewSink(t *testing.T) {
+	l, err := NewLogger("myservice", "1.0.0", WithOutput(io.Discard))
+	if err != nil {
+		t.Errorf("failed to create logger instance, error: %s", err)
+	}
+
+	buf := new(bytes.Buffer)
+	err = l.AddSink("extra", buf, "info", "json")
+	if err != nil {
+		t.Errorf("failed to add sink, error: %s", err)
+	}
+	assert.Contains(t, l.ListSinks(), "extra")
+
+	l.Info("this is a test message")
+	assert.Contains(t, buf.String(), "this is a test message")
+}
+
+func TestThatRemoveSinkStopsFurtherWrites(t *testing.T) {
+	l, err := NewLogger("myservice", "1.0.0", WithOutput(io.Discard))
+	if err != nil {
+		t.Errorf("failed to create logger instance, error: %s", err)
+	}
+
+	buf := new(bytes.Buffer)
+	err = l.AddSink("extra", buf, "info", "json")
+	if err != nil {
+		t.Errorf("failed to add sink, error: %s", err)
+	}
+
+	err = l.RemoveSink("extra")
+	if err != nil {
+		t.Errorf("failed to remove sink, error: %s", err)
+	}
+	assert.NotContains(t, l.ListSinks(), "extra")
+
+	l.Info("should not reach removed sink")
+	assert.Empty(t, buf.String())
+}
+
+func TestThatDerivedLoggerObservesSinksAddedAfterFork(t *testing.T) {
+	l, err := NewLogger("myservice", "1.0.0", WithOutput(io.Discard))
+	if err != nil {
+		t.Errorf("failed to create logger instance, error: %s", err)
+	}
+
+	child := l.With("request_id", "abc-123")
+
+	buf := new(bytes.Buffer)
+	err = l.AddSink("extra", buf, "info", "json")
+	if err != nil {
+		t.Errorf("failed to add sink, error: %s", err)
+	}
+
+	child.Info("this is a test message")
+	assert.Contains(t, buf.String(), "this is a test message")
+}
+
+func TestThatDerivedLoggerObservesSinksRemovedAfterFork(t *testing.T) {
+	l, err := NewLogger("myservice", "1.0.0", WithOutput(io.Discard))
+	if err != nil {
+		t.Errorf("failed to create logger instance, error: %s", err)
+	}
+
+	buf := new(bytes.Buffer)
+	err = l.AddSink("extra", buf, "info", "json")
+	if err != nil {
+		t.Errorf("failed to add sink, error: %s", err)
+	}
+
+	child := l.With("request_id", "abc-123")
+
+	err = l.RemoveSink("extra")
+	if err != nil {
+		t.Errorf("failed to remove sink, error: %s", err)
+	}
+
+	child.Info("should not reach removed sink")
+	assert.Empty(t, buf.String())
+}
+
+func TestThatAddSinkErrorsForUnknownLevel(t *testing.T) {
+	l, err := NewLogger("myservice", "1.0.0", WithOutput(io.Discard))
+	if err != nil {
+		t.Errorf("failed to create logger instance, error: %s", err)
+	}
+
+	err = l.AddSink("extra", io.Discard, "infoo", "json")
+	assert.Error(t, err)
+}
+
+func TestThatRemoveSinkClosesTheSinkDestination(t *testing.T) {
+	l, err := NewLogger("myservice", "1.0.0", WithOutput(io.Discard))
+	if err != nil {
+		t.Errorf("failed to create logger instance, error: %s", err)
+	}
+
+	w := &closingWriter{}
+	err = l.AddSink("extra", w, "info", "json")
+	if err != nil {
+		t.Errorf("failed to add sink, error: %s", err)
+	}
+
+	err = l.RemoveSink("extra")
+	if err != nil {
+		t.Errorf("failed to remove sink, error: %s", err)
+	}
+	assert.True(t, w.closed)
+}
+
+func TestThatCloseClosesEveryRegisteredSink(t *testing.T) {
+	l, err := NewLogger("myservice", "1.0.0", WithOutput(io.Discard))
+	if err != nil {
+		t.Errorf("failed to create logger instance, error: %s", err)
+	}
+
+	w := &closingWriter{}
+	err = l.AddSink("extra", w, "info", "json")
+	if err != nil {
+		t.Errorf("failed to add sink, error: %s", err)
+	}
+
+	err = l.Close()
+	if err != nil {
+		t.Errorf("failed to close logger, error: %s", err)
+	}
+	assert.True(t, w.closed)
+}
+
+func TestThatRemoveSinkErrorsForUnknownName(t *testing.T) {
+	l, err := NewLogger("myservice", "1.0.0", WithOutput(io.Discard))
+	if err != nil {
+		t.Errorf("failed to create logger instance, error: %s", err)
+	}
+
+	err = l.RemoveSink("does-not-exist")
+	assert.Error(t, err)
+}
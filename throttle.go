@@ -0,0 +1,165 @@
+package log
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// samplingConfig holds the live parameters of a samplingCore. Its fields are
+// read and written under mu, so SetSampling takes effect on every logger
+// derived from the one it was built for (With, WithContext, ...), since
+// they all share the same *samplingConfig pointer.
+type samplingConfig struct {
+	mu         sync.RWMutex
+	enabled    bool
+	initial    int
+	thereafter int
+	tick       time.Duration
+}
+
+func (c *samplingConfig) set(initial, thereafter int, tick time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.enabled, c.initial, c.thereafter, c.tick = true, initial, thereafter, tick
+}
+
+func (c *samplingConfig) disable() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.enabled = false
+}
+
+func (c *samplingConfig) get() (enabled bool, initial, thereafter int, tick time.Duration) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.enabled, c.initial, c.thereafter, c.tick
+}
+
+// samplingCore wraps a zapcore.Core with zapcore.NewSamplerWithOptions,
+// rebuilding the sampler whenever its samplingConfig changes so SetSampling
+// can retune or disable sampling at runtime without rebuilding the logger.
+type samplingCore struct {
+	inner zapcore.Core
+	cfg   *samplingConfig
+
+	mu      sync.Mutex
+	built   zapcore.Core
+	builtAt samplingConfig
+}
+
+func (s *samplingCore) current() zapcore.Core {
+	enabled, initial, thereafter, tick := s.cfg.get()
+	if !enabled {
+		return s.inner
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.built == nil || !s.builtAt.enabled || s.builtAt.initial != initial || s.builtAt.thereafter != thereafter || s.builtAt.tick != tick {
+		s.built = zapcore.NewSamplerWithOptions(s.inner, tick, initial, thereafter)
+		s.builtAt = samplingConfig{enabled: true, initial: initial, thereafter: thereafter, tick: tick}
+	}
+	return s.built
+}
+
+func (s *samplingCore) Enabled(lvl zapcore.Level) bool { return s.inner.Enabled(lvl) }
+
+func (s *samplingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &samplingCore{inner: s.inner.With(fields), cfg: s.cfg}
+}
+
+func (s *samplingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return s.current().Check(ent, ce)
+}
+
+// Write is never actually called on the normal logging path: Check above
+// returns s.current().Check(...), which appends the sampler's own inner
+// cores directly to the CheckedEntry, and CheckedEntry.Write calls each of
+// those, not samplingCore.Write. It exists so samplingCore is a complete,
+// independently usable zapcore.Core.
+func (s *samplingCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return s.inner.Write(ent, fields)
+}
+
+func (s *samplingCore) Sync() error { return s.inner.Sync() }
+
+// rateLimitConfig holds the live parameters of a rateLimitCore.
+type rateLimitConfig struct {
+	mu      sync.RWMutex
+	enabled bool
+	perKey  int
+}
+
+func (c *rateLimitConfig) set(perKeyPerSecond int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.enabled, c.perKey = true, perKeyPerSecond
+}
+
+func (c *rateLimitConfig) disable() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.enabled = false
+}
+
+func (c *rateLimitConfig) get() (enabled bool, perKeyPerSecond int) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.enabled, c.perKey
+}
+
+// rateLimitCore wraps a zapcore.Core, dropping entries once more than
+// perKeyPerSecond with the same (level, message) key have been seen within
+// the current one-second window. This guards hot loops where an uncapped
+// info/error log would flood disk and CPU during an incident.
+type rateLimitCore struct {
+	inner zapcore.Core
+	cfg   *rateLimitConfig
+
+	mu          sync.Mutex
+	windowStart time.Time
+	counts      map[string]int
+}
+
+func (r *rateLimitCore) Enabled(lvl zapcore.Level) bool { return r.inner.Enabled(lvl) }
+
+func (r *rateLimitCore) With(fields []zapcore.Field) zapcore.Core {
+	return &rateLimitCore{inner: r.inner.With(fields), cfg: r.cfg, counts: make(map[string]int)}
+}
+
+func (r *rateLimitCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !r.allow(ent) {
+		return ce
+	}
+	return r.inner.Check(ent, ce)
+}
+
+func (r *rateLimitCore) allow(ent zapcore.Entry) bool {
+	enabled, perKey := r.cfg.get()
+	if !enabled {
+		return true
+	}
+
+	key := ent.Level.String() + "|" + ent.Message
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.counts == nil || ent.Time.Sub(r.windowStart) >= time.Second {
+		r.windowStart = ent.Time
+		r.counts = make(map[string]int)
+	}
+	r.counts[key]++
+	return r.counts[key] <= perKey
+}
+
+// Write is never actually called on the normal logging path, for the same
+// reason as samplingCore.Write above: Check appends inner's own cores
+// directly to the CheckedEntry, which writes to each of them itself. It
+// exists so rateLimitCore is a complete, independently usable zapcore.Core.
+func (r *rateLimitCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return r.inner.Write(ent, fields)
+}
+
+func (r *rateLimitCore) Sync() error { return r.inner.Sync() }
@@ -0,0 +1,48 @@
+package log
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThatRateLimitDropsExcessRepeatedMessages(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l, err := NewLogger("myservice", "1.0.0", WithOutput(buf), WithRateLimit(1))
+	if err != nil {
+		t.Errorf("failed to create logger instance, error: %s", err)
+	}
+
+	for i := 0; i < 5; i++ {
+		l.Info("repeated message")
+	}
+	lines := strings.Count(strings.TrimRight(buf.String(), "\n"), "\n") + 1
+	assert.Equal(t, 1, lines)
+}
+
+func TestThatSetRateLimitDisablesAtRuntime(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l, err := NewLogger("myservice", "1.0.0", WithOutput(buf), WithRateLimit(1))
+	if err != nil {
+		t.Errorf("failed to create logger instance, error: %s", err)
+	}
+
+	l.SetRateLimit(0)
+	for i := 0; i < 3; i++ {
+		l.Info("repeated message")
+	}
+	lines := strings.Count(strings.TrimRight(buf.String(), "\n"), "\n") + 1
+	assert.Equal(t, 3, lines)
+}
+
+func TestThatWithSamplingConfiguresLogger(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l, err := NewLogger("myservice", "1.0.0", WithOutput(buf), WithSampling(2, 3, time.Second))
+	if err != nil {
+		t.Errorf("failed to create logger instance, error: %s", err)
+	}
+	assert.True(t, l.config.samplingEnabled)
+}